@@ -2,166 +2,1619 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"mime/multipart"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/pion/rtp"
 )
 
+// wsUpgrader upgrades /rtp/:id connections. Origin checking is intentionally
+// permissive: this control server is only reachable on the robot's own LAN,
+// same as the rest of the endpoints in this file.
+var wsUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// Camera health states reported through / and /health/:id.
+const (
+	healthStarting  = "starting"
+	healthHealthy   = "healthy"
+	healthUnhealthy = "unhealthy"
+	healthBackoff   = "backoff"
+	healthDisabled  = "disabled"
+)
+
+const (
+	// maxRestartsPerWindow trips the circuit breaker: if a camera restarts
+	// more than this many times within restartWindow, it is disabled until
+	// an operator intervenes, mirroring self-node-remediation's remediation
+	// backoff.
+	maxRestartsPerWindow = 5
+	restartWindow        = 5 * time.Minute
+
+	// RTSP readiness probing after (re)start.
+	rtspProbeAttemptTimeout = 2 * time.Second
+	rtspProbeInterval       = 500 * time.Millisecond
+	rtspProbeDeadline       = 10 * time.Second
+)
+
+// broadcastConfigPath is where persisted broadcast destinations are stored so
+// they can be restored across manager restarts.
+const broadcastConfigPath = "./config/broadcasts.json"
+
+// broadcastStderrLines caps how many trailing stderr lines we keep in memory
+// per broadcast so /broadcast/:id can report why an RTMP/HLS handshake failed
+// without operators having to SSH into the Jetson to read log files.
+const broadcastStderrLines = 20
+
 type CameraInfo struct {
 	Device  string `json:"device"`
 	Product string `json:"product"`
 }
 
-type CameraProcess struct {
-	info       CameraInfo
-	rtspPath   string
-	cmd        *exec.Cmd
-	startedAt  time.Time
-	mutex      sync.Mutex
-	restarts   int
-	cancelFunc context.CancelFunc
-}
+// BroadcastConfig describes a single republishing destination for a camera's
+// capture stream, e.g. an RTMP endpoint on a streaming platform.
+type BroadcastConfig struct {
+	URL    string `json:"url"`
+	Format string `json:"format"` // "rtmp", "hls", or "srt"
+}
+
+// BroadcastProcess tracks the child GStreamer pipeline that republishes a
+// camera's local RTSP stream to an external destination. Its lifecycle is
+// independent of the capture CameraProcess: it has its own mutex, its own
+// exec.Cmd, and its own reconnect loop, so starting/stopping a broadcast (or
+// the capture pipeline restarting) never touches WebRTC/RTSP consumers.
+type BroadcastProcess struct {
+	cfg          BroadcastConfig
+	cmd          *exec.Cmd
+	cancelFunc   context.CancelFunc
+	mutex        sync.Mutex
+	startedAt    time.Time
+	restarts     int
+	stopped      bool // set by StopBroadcast so the reconnect loop gives up
+	reconnecting bool // true while superviseBroadcast is sleeping in its backoff window, so StartBroadcast can't race in and launch a second child
+	lastErr      string
+	stderrTail   []string
+}
+
+// StreamProfile describes one encoded rendition of a physical camera, e.g. a
+// 1280x720 "main" stream and a 640x360 "sub" stream sharing the same device.
+type StreamProfile struct {
+	Name        string `json:"name"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Framerate   int    `json:"framerate"`
+	Bitrate     int    `json:"bitrate"`     // bits/sec
+	RtspSubPath string `json:"rtspSubPath"` // appended to the camera's base RTSP path, e.g. "main" or "sub"
+}
+
+// defaultStreamProfiles returns the main+sub rendition pair every camera
+// starts with: a full-res stream for recording/ML and a cheap low-res stream
+// that stays responsive even if main is saturated.
+func defaultStreamProfiles() []StreamProfile {
+	return []StreamProfile{
+		{Name: "main", Width: 1280, Height: 720, Framerate: 30, Bitrate: 2000000, RtspSubPath: "main"},
+		{Name: "sub", Width: 640, Height: 360, Framerate: 15, Bitrate: 500000, RtspSubPath: "sub"},
+	}
+}
+
+// profileProcess is one restartable RTSP-mount unit for a single
+// StreamProfile of a camera: a test-launch child that re-payloads the
+// profile's share of the shared captureProcess (read over a loopback UDP
+// port), its supervisor, and circuit breaker. Each profile gets its own, so a
+// wedged sub-stream can't take out main and vice versa; neither one ever
+// opens the physical device directly (see captureProcess).
+type profileProcess struct {
+	profile    StreamProfile
+	rtspPath   string
+	udpPort    int // loopback port captureProcess's tee branch for this profile writes RTP to; assigned once in NewManager
+	cmd        *exec.Cmd
+	cancelFunc context.CancelFunc
+	startedAt  time.Time
+	mutex      sync.Mutex
+	restarts   int
+
+	stopped      bool   // set by StopCameraProfile so the supervisor doesn't auto-restart
+	disabled     bool   // breaker tripped (or operator-quarantined) via /disable/:id/:profile
+	health       string // one of the health* constants
+	lastExitCode int
+	lastExitAt   time.Time
+	restartTimes []time.Time // restart timestamps within restartWindow, for the breaker
+}
+
+// captureProcess is the single process that opens a camera's physical device
+// and tees the raw frames into one encoded RTP branch per stream profile,
+// each written to that profile's loopback UDP port (profileProcess.udpPort).
+// Most V4L2/UVC webcams only allow one streaming client per device node, so
+// every profile's test-launch mount reads from its own udpsrc instead of
+// opening the device a second time; this is the only process that ever
+// touches /dev/videoN.
+type captureProcess struct {
+	mutex      sync.Mutex
+	cmd        *exec.Cmd
+	cancelFunc context.CancelFunc
+	stopped    bool // set by stopCaptureIfIdle so its supervisor doesn't auto-restart
+	restarts   int
+}
+
+type CameraProcess struct {
+	info         CameraInfo
+	basePath     string // e.g. "rtsp://127.0.0.1:8554/cam0", before the per-profile sub-path
+	profiles     map[string]*profileProcess
+	profileOrder []string // profile names in configured order, for stable listing
+
+	mutex     sync.Mutex // guards broadcast/mjpeg/rtp/capture below, which are camera-wide, not per-profile
+	broadcast *BroadcastProcess
+	mjpeg     *frameHub       // lazily-started decode branch shared by /snapshot and /mjpeg
+	rtp       *RTPHub         // lazily-started passthrough branch shared by /rtp subscribers
+	capture   *captureProcess // lazily-started, shared device-capture branch backing every stream profile
+}
+
+// mainRTSPPath returns the RTSP URL of the camera's "main" profile, used as
+// the source for features that consume a single stream per camera
+// (broadcast, MJPEG, RTP passthrough).
+func (cp *CameraProcess) mainRTSPPath() string {
+	if pp, ok := cp.profiles["main"]; ok {
+		return pp.rtspPath
+	}
+	return cp.basePath
+}
+
+// RTPHub re-payloads a camera's local RTSP stream (no decode/re-encode) and
+// fans the resulting RTP packets out to every passthrough subscriber, so a
+// WebRTC bridge, recorder, and cloud uploader can share one GStreamer branch.
+type RTPHub struct {
+	mutex       sync.Mutex
+	cmd         *exec.Cmd
+	cancelFunc  context.CancelFunc
+	udpConn     *net.UDPConn
+	subscribers map[chan *rtp.Packet]struct{}
+	payloadType uint8
+
+	// generation increments every time ensureRTPHub starts a new cmd; see
+	// frameHub.generation for why pumpRTP's cleanup checks it before
+	// touching shared hub state.
+	generation uint64
+
+	// done is closed by pumpRTP once it has reaped cmd and cleared hub
+	// state for the current generation, so Subscribe's unsubscribe func can
+	// wait for that to actually happen instead of reaping cmd itself.
+	done chan struct{}
+}
+
+// frameHub decodes a camera's RTSP stream to JPEG once and fans the frames
+// out to every subscriber, so N browser tabs don't cost N decodes. It is
+// started on first subscriber and torn down when the last one leaves.
+type frameHub struct {
+	mutex       sync.Mutex
+	cmd         *exec.Cmd
+	cancelFunc  context.CancelFunc
+	subscribers map[chan []byte]struct{}
+
+	// generation increments every time ensureMJPEGHub starts a new cmd. A
+	// pumpMJPEG goroutine stamps the generation it was started for and
+	// checks it still matches before tearing down hub state in its cleanup
+	// path, so a stale goroutine from a process that was already replaced
+	// can't clobber the new one's cmd/cancelFunc/subscribers.
+	generation uint64
+
+	// done is closed by pumpMJPEG once it has reaped cmd and cleared hub
+	// state for the current generation, so subscribeMJPEG's unsubscribe
+	// func can wait for that to actually happen instead of reaping cmd
+	// itself.
+	done chan struct{}
+}
+
+type Manager struct {
+	cameras map[int]*CameraProcess
+	lock    sync.Mutex
+}
+
+func NewManager(cameraList []CameraInfo) *Manager {
+	m := &Manager{
+		cameras: make(map[int]*CameraProcess),
+	}
+	for i, cam := range cameraList {
+		cp := &CameraProcess{
+			info:     cam,
+			basePath: fmt.Sprintf("rtsp://127.0.0.1:8554/cam%d", i),
+			profiles: make(map[string]*profileProcess),
+		}
+		for _, profile := range defaultStreamProfiles() {
+			port, err := allocateLoopbackPort()
+			if err != nil {
+				log.Printf("camera %d stream %q: failed to allocate capture loopback port: %v", i, profile.Name, err)
+			}
+			cp.profiles[profile.Name] = &profileProcess{
+				profile:  profile,
+				rtspPath: fmt.Sprintf("%s/%s", cp.basePath, profile.RtspSubPath),
+				udpPort:  port,
+			}
+			cp.profileOrder = append(cp.profileOrder, profile.Name)
+		}
+		m.cameras[i] = cp
+	}
+	return m
+}
+
+// allocateLoopbackPort asks the OS for a free UDP port on 127.0.0.1 and
+// immediately releases it, so the shared captureProcess's udpsink and the
+// corresponding profile's udpsrc can agree on a port number before either
+// child process starts.
+func allocateLoopbackPort() (int, error) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 0})
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).Port, nil
+}
+
+// LoadBroadcastConfigs restores persisted broadcast destinations (keyed by
+// camera index) and starts them, so a manager restart picks broadcasts back
+// up without operator intervention.
+func (m *Manager) LoadBroadcastConfigs() {
+	data, err := os.ReadFile(broadcastConfigPath)
+	if err != nil {
+		return // nothing persisted yet, or not readable; nothing to restore
+	}
+
+	var saved map[string]BroadcastConfig
+	if err := json.Unmarshal(data, &saved); err != nil {
+		log.Printf("broadcast: failed to parse %s: %v", broadcastConfigPath, err)
+		return
+	}
+
+	for idStr, cfg := range saved {
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			continue
+		}
+		if err := m.StartBroadcast(id, cfg.URL, cfg.Format); err != nil {
+			log.Printf("broadcast: failed to restore camera %d -> %s: %v", id, cfg.URL, err)
+		}
+	}
+}
+
+// persistBroadcastConfigs snapshots the currently-configured (not necessarily
+// running) broadcast destinations to disk.
+func (m *Manager) persistBroadcastConfigs() {
+	m.lock.Lock()
+	saved := make(map[string]BroadcastConfig)
+	for i, cp := range m.cameras {
+		cp.mutex.Lock()
+		if cp.broadcast != nil {
+			saved[strconv.Itoa(i)] = cp.broadcast.cfg
+		}
+		cp.mutex.Unlock()
+	}
+	m.lock.Unlock()
+
+	os.MkdirAll(filepath.Dir(broadcastConfigPath), 0755)
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		log.Printf("broadcast: failed to marshal config: %v", err)
+		return
+	}
+	if err := os.WriteFile(broadcastConfigPath, data, 0644); err != nil {
+		log.Printf("broadcast: failed to persist config: %v", err)
+	}
+}
+
+// StartCamera starts every configured stream profile (main + sub) for the
+// given camera index. Use StartCameraProfile to bring up a single profile.
+func (m *Manager) StartCamera(idx int) error {
+	m.lock.Lock()
+	cp, ok := m.cameras[idx]
+	m.lock.Unlock()
+	if !ok {
+		return fmt.Errorf("camera %d not found", idx)
+	}
+
+	var firstErr error
+	for _, name := range cp.profileOrder {
+		if err := m.StartCameraProfile(idx, name); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// StartCameraProfile will spawn a gst-rtsp-server test-launch process for the
+// given camera index and stream profile ("main" or "sub").
+// The test-launch pipeline only re-payloads RTP; the H.264 encoding (Jetson
+// hardware encoder nvv4l2h264enc) runs in the camera's shared capture
+// pipeline, which is started first if it isn't already running.
+func (m *Manager) StartCameraProfile(idx int, profileName string) error {
+	m.lock.Lock()
+	cp, ok := m.cameras[idx]
+	m.lock.Unlock()
+	if !ok {
+		return fmt.Errorf("camera %d not found", idx)
+	}
+	pp, ok := cp.profiles[profileName]
+	if !ok {
+		return fmt.Errorf("camera %d has no %q stream profile", idx, profileName)
+	}
+
+	pp.mutex.Lock()
+	if pp.cmd != nil {
+		pp.mutex.Unlock()
+		return fmt.Errorf("camera %d stream %q already started", idx, profileName)
+	}
+	if pp.disabled {
+		pp.mutex.Unlock()
+		return fmt.Errorf("camera %d stream %q is disabled; POST /disable/:id/:profile tripped the breaker or an operator quarantined it", idx, profileName)
+	}
+	pp.restarts = 0
+	pp.restartTimes = nil
+	err := m.startProfileLocked(idx, cp, pp)
+	pp.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// Actively probe RTSP readiness instead of hoping a fixed sleep was long enough.
+	m.probeAndSetHealth(idx, pp)
+	return nil
+}
+
+// startProfileLocked ensures the camera's shared capture branch is running,
+// then spawns the test-launch child for pp and arms its supervisor goroutine.
+// Callers must hold pp.mutex and have already verified pp.cmd == nil.
+func (m *Manager) startProfileLocked(idx int, cp *CameraProcess, pp *profileProcess) error {
+	cp.mutex.Lock()
+	captureErr := m.ensureCaptureLocked(idx, cp)
+	cp.mutex.Unlock()
+	if captureErr != nil {
+		return captureErr
+	}
+
+	// The profile mount never touches the device itself: it just re-payloads
+	// the RTP stream captureProcess's tee branch already sent to udpPort.
+	pipeline := fmt.Sprintf("( udpsrc port=%d caps='application/x-rtp,media=video,encoding-name=H264,payload=96,clock-rate=90000' ! rtpjitterbuffer ! rtph264depay ! rtph264pay name=pay0 pt=96 config-interval=1 )", pp.udpPort)
+
+	// test-launch is the sample binary from gst-rtsp-server repo that runs a pipeline as RTSP server.
+	// If you don't have test-launch, install gst-rtsp-server or change this to another method.
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, "test-launch", pipeline)
+	// send stdout/stderr to files for debugging
+	logDir := "./logs"
+	os.MkdirAll(logDir, 0755)
+	stdoutFile, _ := os.OpenFile(filepath.Join(logDir, fmt.Sprintf("cam%d_%s_stdout.log", idx, pp.profile.Name)), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	stderrFile, _ := os.OpenFile(filepath.Join(logDir, fmt.Sprintf("cam%d_%s_stderr.log", idx, pp.profile.Name)), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	cmd.Stdout = stdoutFile
+	cmd.Stderr = stderrFile
+
+	// Start the process
+	if err := cmd.Start(); err != nil {
+		cancel()
+		stdoutFile.Close()
+		stderrFile.Close()
+		return fmt.Errorf("failed to start test-launch for camera %d stream %q: %w", idx, pp.profile.Name, err)
+	}
+
+	pp.cmd = cmd
+	pp.cancelFunc = cancel
+	pp.startedAt = time.Now()
+	pp.stopped = false
+	pp.health = healthStarting
+
+	go m.superviseProfile(idx, cp, pp, cmd, stdoutFile, stderrFile)
+
+	log.Printf("Started camera %d stream %q -> %s (pipeline: %s)", idx, pp.profile.Name, pp.rtspPath, pipeline)
+	return nil
+}
+
+// buildCapturePipeline builds the shared GStreamer pipeline that opens cp's
+// device once and tees the raw frames into one encoded RTP branch per
+// configured stream profile, each written to that profile's loopback UDP
+// port. The first profile in profileOrder ("main") is captured at its native
+// resolution; every other profile is scaled down from it via videoscale.
+func buildCapturePipeline(cp *CameraProcess) (string, error) {
+	if len(cp.profileOrder) == 0 {
+		return "", fmt.Errorf("camera %s has no stream profiles configured", cp.info.Device)
+	}
+
+	nativeName := cp.profileOrder[0]
+	var b strings.Builder
+	native := cp.profiles[nativeName].profile
+	fmt.Fprintf(&b, "( v4l2src device=%s ! video/x-raw,width=%d,height=%d,framerate=%d/1 ! tee name=t ", cp.info.Device, native.Width, native.Height, native.Framerate)
+
+	for _, name := range cp.profileOrder {
+		pp := cp.profiles[name]
+		if pp.udpPort == 0 {
+			return "", fmt.Errorf("stream profile %q has no loopback port assigned", name)
+		}
+		sp := pp.profile
+		b.WriteString("t. ! queue ")
+		if name != nativeName {
+			fmt.Fprintf(&b, "! videoscale ! videorate ! video/x-raw,width=%d,height=%d,framerate=%d/1 ", sp.Width, sp.Height, sp.Framerate)
+		}
+		fmt.Fprintf(&b, "! nvvidconv ! 'video/x-raw(memory:NVMM),format=NV12' ! nvv4l2h264enc bitrate=%d ! h264parse ! rtph264pay pt=96 config-interval=1 ! udpsink host=127.0.0.1 port=%d sync=false ", sp.Bitrate, pp.udpPort)
+	}
+	b.WriteString(")")
+	return b.String(), nil
+}
+
+// ensureCaptureLocked starts cp's shared device-capture pipeline if it isn't
+// already running. Callers must hold cp.mutex.
+func (m *Manager) ensureCaptureLocked(idx int, cp *CameraProcess) error {
+	if cp.capture == nil {
+		cp.capture = &captureProcess{}
+	}
+	capProc := cp.capture
+
+	capProc.mutex.Lock()
+	defer capProc.mutex.Unlock()
+	if capProc.cmd != nil {
+		// A concurrent stopCaptureIfIdle may have set stopped and called
+		// cancel() on this same cmd but not reaped it yet. The caller here
+		// wants the branch running, so clear stopped now: if the teardown
+		// does finish the race, superviseCapture reads a fresh stopped=false
+		// and restarts instead of giving up on a capture branch a caller is
+		// actively depending on.
+		capProc.stopped = false
+		return nil
+	}
+
+	pipeline, err := buildCapturePipeline(cp)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, "gst-launch-1.0", "-e", pipeline)
+
+	logDir := "./logs"
+	os.MkdirAll(logDir, 0755)
+	stderrFile, _ := os.OpenFile(filepath.Join(logDir, fmt.Sprintf("cam%d_capture_stderr.log", idx)), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	cmd.Stderr = stderrFile
+
+	if err := cmd.Start(); err != nil {
+		cancel()
+		stderrFile.Close()
+		return fmt.Errorf("camera %d: failed to start shared capture pipeline: %w", idx, err)
+	}
+
+	capProc.stopped = false
+	capProc.cmd = cmd
+	capProc.cancelFunc = cancel
+
+	go m.superviseCapture(idx, cp, capProc, cmd, stderrFile)
+
+	log.Printf("camera %d: started shared capture branch on device %s (pipeline: %s)", idx, cp.info.Device, pipeline)
+	return nil
+}
+
+// superviseCapture waits for the shared capture pipeline to exit and, unless
+// it was stopped intentionally (via stopCaptureIfIdle, once every stream
+// profile has stopped), restarts it with exponential backoff. A crash here
+// pauses frames for every stream profile until it reconnects, but it never
+// causes a second v4l2src open of the device.
+func (m *Manager) superviseCapture(idx int, cp *CameraProcess, capProc *captureProcess, cmd *exec.Cmd, stderr *os.File) {
+	err := cmd.Wait()
+	stderr.Close()
+
+	capProc.mutex.Lock()
+	capProc.cmd = nil
+	if capProc.cancelFunc != nil {
+		capProc.cancelFunc()
+		capProc.cancelFunc = nil
+	}
+	stopped := capProc.stopped
+	capProc.mutex.Unlock()
+
+	if err != nil {
+		log.Printf("camera %d: shared capture pipeline exited with error: %v", idx, err)
+	} else {
+		log.Printf("camera %d: shared capture pipeline exited cleanly", idx)
+	}
+
+	if stopped {
+		return
+	}
+
+	backoff := time.Second
+	for {
+		log.Printf("camera %d: shared capture pipeline restarting in %s", idx, backoff)
+		time.Sleep(backoff)
+
+		capProc.mutex.Lock()
+		giveUp := capProc.stopped
+		capProc.mutex.Unlock()
+		if giveUp {
+			return
+		}
+
+		cp.mutex.Lock()
+		launchErr := m.ensureCaptureLocked(idx, cp)
+		cp.mutex.Unlock()
+		if launchErr == nil {
+			return // superviseCapture is re-armed by ensureCaptureLocked for the new cmd
+		}
+
+		capProc.mutex.Lock()
+		capProc.restarts++
+		capProc.mutex.Unlock()
+
+		backoff *= 2
+		if backoff > 30*time.Second {
+			backoff = 30 * time.Second
+		}
+	}
+}
+
+// stopCaptureIfIdle tears down cp's shared capture pipeline once every
+// stream profile has stopped, so it isn't left running against a device no
+// profile is reading from anymore.
+func (m *Manager) stopCaptureIfIdle(idx int, cp *CameraProcess) {
+	for _, name := range cp.profileOrder {
+		pp := cp.profiles[name]
+		pp.mutex.Lock()
+		running := pp.cmd != nil
+		pp.mutex.Unlock()
+		if running {
+			return
+		}
+	}
+
+	cp.mutex.Lock()
+	capProc := cp.capture
+	cp.mutex.Unlock()
+	if capProc == nil {
+		return
+	}
+
+	capProc.mutex.Lock()
+	capProc.stopped = true
+	cancel := capProc.cancelFunc
+	cmd := capProc.cmd
+	capProc.mutex.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	if cmd != nil {
+		done := make(chan struct{})
+		go func() {
+			cmd.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			if cmd.Process != nil {
+				cmd.Process.Kill()
+			}
+		}
+	}
+
+	log.Printf("camera %d: stopped shared capture pipeline (no stream profiles running)", idx)
+}
+
+// superviseProfile waits for the profile's test-launch child to exit. If it wasn't stopped
+// intentionally via StopCameraProfile, it schedules a backed-off restart,
+// subject to the per-window circuit breaker.
+func (m *Manager) superviseProfile(idx int, cp *CameraProcess, pp *profileProcess, cmd *exec.Cmd, stdout, stderr *os.File) {
+	err := cmd.Wait()
+	stdout.Close()
+	stderr.Close()
+
+	pp.mutex.Lock()
+	pp.cmd = nil
+	if pp.cancelFunc != nil {
+		pp.cancelFunc()
+		pp.cancelFunc = nil
+	}
+	exitCode := -1
+	if cmd.ProcessState != nil {
+		exitCode = cmd.ProcessState.ExitCode()
+	}
+	pp.lastExitCode = exitCode
+	pp.lastExitAt = time.Now()
+	stoppedByOperator := pp.stopped
+	pp.mutex.Unlock()
+
+	if err != nil {
+		log.Printf("camera %d stream %q process exited with error: %v (logs: %s, %s)", idx, pp.profile.Name, err, stdout.Name(), stderr.Name())
+	} else {
+		log.Printf("camera %d stream %q process exited cleanly", idx, pp.profile.Name)
+	}
+
+	if stoppedByOperator {
+		return
+	}
+
+	m.scheduleProfileRestart(idx, cp, pp)
+}
+
+// scheduleProfileRestart applies the exponential backoff + circuit breaker
+// policy and relaunches the stream profile, similar to how
+// self-node-remediation bounds remediation attempts per node.
+func (m *Manager) scheduleProfileRestart(idx int, cp *CameraProcess, pp *profileProcess) {
+	pp.mutex.Lock()
+	if pp.disabled {
+		pp.mutex.Unlock()
+		return
+	}
+
+	now := time.Now()
+	cutoff := now.Add(-restartWindow)
+	kept := pp.restartTimes[:0]
+	for _, t := range pp.restartTimes {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	pp.restartTimes = append(kept, now)
+
+	if len(pp.restartTimes) > maxRestartsPerWindow {
+		pp.disabled = true
+		pp.health = healthDisabled
+		pp.mutex.Unlock()
+		log.Printf("camera %d stream %q: tripped breaker after %d restarts within %s; disabling until an operator intervenes", idx, pp.profile.Name, len(pp.restartTimes), restartWindow)
+		return
+	}
+
+	pp.restarts++
+	attempt := len(pp.restartTimes)
+	pp.health = healthBackoff
+	pp.mutex.Unlock()
+
+	backoff := time.Duration(1<<uint(attempt-1)) * time.Second
+	if backoff > 30*time.Second {
+		backoff = 30 * time.Second
+	}
+	log.Printf("camera %d stream %q: restarting in %s (attempt %d)", idx, pp.profile.Name, backoff, attempt)
+	time.Sleep(backoff)
+
+	pp.mutex.Lock()
+	if pp.disabled {
+		pp.mutex.Unlock()
+		return
+	}
+	launchErr := m.startProfileLocked(idx, cp, pp)
+	pp.mutex.Unlock()
+
+	if launchErr != nil {
+		log.Printf("camera %d stream %q: restart attempt failed: %v", idx, pp.profile.Name, launchErr)
+		m.scheduleProfileRestart(idx, cp, pp)
+		return
+	}
+
+	go m.probeAndSetHealth(idx, pp)
+}
+
+// probeAndSetHealth polls the stream profile's RTSP URL with OPTIONS requests
+// until it responds or rtspProbeDeadline elapses, and records the resulting
+// health state.
+func (m *Manager) probeAndSetHealth(idx int, pp *profileProcess) {
+	deadline := time.Now().Add(rtspProbeDeadline)
+	for {
+		if err := probeRTSP(pp.rtspPath, rtspProbeAttemptTimeout); err == nil {
+			pp.mutex.Lock()
+			if pp.cmd != nil {
+				pp.health = healthHealthy
+			}
+			pp.mutex.Unlock()
+			return
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(rtspProbeInterval)
+	}
+
+	pp.mutex.Lock()
+	if pp.cmd != nil {
+		pp.health = healthUnhealthy
+	}
+	pp.mutex.Unlock()
+	log.Printf("camera %d stream %q: RTSP readiness probe timed out after %s", idx, pp.profile.Name, rtspProbeDeadline)
+}
+
+// probeRTSP issues a bare RTSP OPTIONS request and reports whether the server
+// answered with a 200. It only needs a TCP round trip, so it doesn't pull in
+// a full RTSP client just to check readiness.
+func probeRTSP(rtspURL string, timeout time.Duration) error {
+	u, err := url.Parse(rtspURL)
+	if err != nil {
+		return fmt.Errorf("invalid rtsp url %q: %w", rtspURL, err)
+	}
+
+	conn, err := net.DialTimeout("tcp", u.Host, timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	req := fmt.Sprintf("OPTIONS %s RTSP/1.0\r\nCSeq: 1\r\n\r\n", rtspURL)
+	if _, err := conn.Write([]byte(req)); err != nil {
+		return err
+	}
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "RTSP/1.0 200") {
+		return fmt.Errorf("unexpected RTSP response: %s", strings.TrimSpace(line))
+	}
+	return nil
+}
+
+// StopCamera stops every configured stream profile for the given camera
+// index. Use StopCameraProfile to stop a single profile.
+func (m *Manager) StopCamera(idx int) error {
+	m.lock.Lock()
+	cp, ok := m.cameras[idx]
+	m.lock.Unlock()
+	if !ok {
+		return fmt.Errorf("camera %d not found", idx)
+	}
+
+	var firstErr error
+	for _, name := range cp.profileOrder {
+		if err := m.StopCameraProfile(idx, name); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// StopCameraProfile stops the given camera's stream profile.
+func (m *Manager) StopCameraProfile(idx int, profileName string) error {
+	m.lock.Lock()
+	cp, ok := m.cameras[idx]
+	m.lock.Unlock()
+	if !ok {
+		return fmt.Errorf("camera %d not found", idx)
+	}
+	pp, ok := cp.profiles[profileName]
+	if !ok {
+		return fmt.Errorf("camera %d has no %q stream profile", idx, profileName)
+	}
+
+	pp.mutex.Lock()
+
+	if pp.cmd == nil {
+		pp.mutex.Unlock()
+		return fmt.Errorf("camera %d stream %q not running", idx, profileName)
+	}
+
+	// Mark as an intentional stop so the supervisor doesn't try to restart it.
+	pp.stopped = true
+
+	// Graceful shutdown via cancel func
+	if pp.cancelFunc != nil {
+		pp.cancelFunc()
+	}
+
+	// give it a short time, then kill
+	done := make(chan struct{})
+	go func() {
+		pp.cmd.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		if pp.cmd.Process != nil {
+			pp.cmd.Process.Kill()
+		}
+	}
+
+	pp.cmd = nil
+	pp.cancelFunc = nil
+	pp.health = ""
+	pp.mutex.Unlock()
+
+	log.Printf("Stopped camera %d stream %q", idx, profileName)
+	// Stop the shared capture branch too once nothing is reading from it
+	// anymore, so it isn't left running against the device for no reason.
+	m.stopCaptureIfIdle(idx, cp)
+	return nil
+}
+
+// DisableCamera stops every stream profile (if running) and trips their
+// breakers so StartCamera and the supervisor both refuse to bring them back
+// until an operator investigates.
+func (m *Manager) DisableCamera(idx int) error {
+	m.lock.Lock()
+	cp, ok := m.cameras[idx]
+	m.lock.Unlock()
+	if !ok {
+		return fmt.Errorf("camera %d not found", idx)
+	}
+
+	var firstErr error
+	for _, name := range cp.profileOrder {
+		if err := m.DisableCameraProfile(idx, name); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// DisableCameraProfile stops the stream profile (if running) and trips its
+// breaker so StartCameraProfile and the supervisor both refuse to bring it
+// back until an operator investigates.
+func (m *Manager) DisableCameraProfile(idx int, profileName string) error {
+	m.lock.Lock()
+	cp, ok := m.cameras[idx]
+	m.lock.Unlock()
+	if !ok {
+		return fmt.Errorf("camera %d not found", idx)
+	}
+	pp, ok := cp.profiles[profileName]
+	if !ok {
+		return fmt.Errorf("camera %d has no %q stream profile", idx, profileName)
+	}
+
+	_ = m.StopCameraProfile(idx, profileName) // best-effort; stream may already be stopped
+
+	pp.mutex.Lock()
+	pp.disabled = true
+	pp.health = healthDisabled
+	pp.mutex.Unlock()
+
+	log.Printf("camera %d stream %q disabled by operator", idx, profileName)
+	return nil
+}
+
+// HealthStatus reports the supervisor's view of every stream profile of
+// camera idx for /health/:id.
+func (m *Manager) HealthStatus(idx int) (gin.H, error) {
+	m.lock.Lock()
+	cp, ok := m.cameras[idx]
+	m.lock.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("camera %d not found", idx)
+	}
+
+	streams := make([]gin.H, 0, len(cp.profileOrder))
+	for _, name := range cp.profileOrder {
+		s, err := m.ProfileHealthStatus(idx, name)
+		if err != nil {
+			return nil, err
+		}
+		streams = append(streams, s)
+	}
+
+	return gin.H{
+		"id":      idx,
+		"streams": streams,
+	}, nil
+}
+
+// ProfileHealthStatus reports the supervisor's view of a single stream
+// profile of camera idx for /health/:id/:profile.
+func (m *Manager) ProfileHealthStatus(idx int, profileName string) (gin.H, error) {
+	m.lock.Lock()
+	cp, ok := m.cameras[idx]
+	m.lock.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("camera %d not found", idx)
+	}
+	pp, ok := cp.profiles[profileName]
+	if !ok {
+		return nil, fmt.Errorf("camera %d has no %q stream profile", idx, profileName)
+	}
+
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	lastExitAt := ""
+	if !pp.lastExitAt.IsZero() {
+		lastExitAt = pp.lastExitAt.Format(time.RFC3339)
+	}
+	return gin.H{
+		"profile":      profileName,
+		"health":       pp.health,
+		"restarts":     pp.restarts,
+		"lastExitCode": pp.lastExitCode,
+		"lastExitAt":   lastExitAt,
+		"disabled":     pp.disabled,
+	}, nil
+}
+
+// stderrTailWriter is an io.Writer that keeps the last N newline-delimited
+// lines written to it, in addition to passing everything through to an
+// underlying writer (typically the per-process stderr log file).
+type stderrTailWriter struct {
+	bp   *BroadcastProcess
+	max  int
+	buf  bytes.Buffer
+	next io.Writer
+}
+
+func (w *stderrTailWriter) Write(p []byte) (int, error) {
+	if w.next != nil {
+		w.next.Write(p)
+	}
+	w.buf.Write(p)
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			// push back the partial line for the next Write call
+			w.buf.Reset()
+			w.buf.WriteString(line)
+			break
+		}
+		w.bp.mutex.Lock()
+		w.bp.stderrTail = append(w.bp.stderrTail, line)
+		if len(w.bp.stderrTail) > w.max {
+			w.bp.stderrTail = w.bp.stderrTail[len(w.bp.stderrTail)-w.max:]
+		}
+		w.bp.mutex.Unlock()
+	}
+	return len(p), nil
+}
+
+// validateBroadcastURL rejects anything in url that gst_parse_launch would
+// treat as pipeline syntax (quotes, "!", whitespace, control characters)
+// instead of an opaque property value, and enforces the URI scheme each
+// format's sink element expects. Without this, a crafted url (e.g. containing
+// `" ! filesink location=...`) could break out of the sink's location/uri
+// property and inject arbitrary elements into the broadcast pipeline.
+func validateBroadcastURL(url, format string) error {
+	if url == "" {
+		return fmt.Errorf("url is required")
+	}
+	for _, r := range url {
+		if r <= 0x20 || r == 0x7f || r == '"' || r == '\'' || r == '!' {
+			return fmt.Errorf("url contains a disallowed character")
+		}
+	}
+
+	switch format {
+	case "rtmp":
+		if !strings.HasPrefix(url, "rtmp://") && !strings.HasPrefix(url, "rtmps://") {
+			return fmt.Errorf("rtmp broadcast url must start with rtmp:// or rtmps://")
+		}
+	case "srt":
+		if !strings.HasPrefix(url, "srt://") {
+			return fmt.Errorf("srt broadcast url must start with srt://")
+		}
+	}
+	// hls's url is a local hlssink2 file-pattern location, not a URI, so no
+	// scheme check applies beyond the character checks above.
+	return nil
+}
+
+// buildBroadcastPipeline builds a GStreamer pipeline string that consumes the
+// camera's local RTSP stream and republishes it to url in the given format.
+func buildBroadcastPipeline(source, url, format string) (string, error) {
+	if err := validateBroadcastURL(url, format); err != nil {
+		return "", err
+	}
+
+	switch format {
+	case "rtmp":
+		return fmt.Sprintf("rtspsrc location=%s ! rtph264depay ! h264parse ! flvmux streamable=true ! rtmpsink location=%s live=true", source, url), nil
+	case "hls":
+		return fmt.Sprintf("rtspsrc location=%s ! rtph264depay ! h264parse ! hlssink2 location=%s max-files=6", source, url), nil
+	case "srt":
+		return fmt.Sprintf("rtspsrc location=%s ! rtph264depay ! h264parse ! mpegtsmux ! srtsink uri=%s", source, url), nil
+	default:
+		return "", fmt.Errorf("unsupported broadcast format %q (want rtmp, hls, or srt)", format)
+	}
+}
+
+// StartBroadcast spawns a broadcast pipeline that republishes camera idx's
+// local RTSP stream to url. The pipeline runs independently of the capture
+// pipeline: capture restarts do not stop it, and it reconnects on its own
+// with backoff if the source disappears.
+func (m *Manager) StartBroadcast(idx int, url string, format string) error {
+	m.lock.Lock()
+	cp, ok := m.cameras[idx]
+	m.lock.Unlock()
+	if !ok {
+		return fmt.Errorf("camera %d not found", idx)
+	}
+
+	if _, err := buildBroadcastPipeline(cp.mainRTSPPath(), url, format); err != nil {
+		return err
+	}
+
+	cp.mutex.Lock()
+	existing := cp.broadcast
+	cp.mutex.Unlock()
+	if existing != nil {
+		existing.mutex.Lock()
+		busy := existing.cmd != nil || existing.reconnecting
+		existing.mutex.Unlock()
+		if busy {
+			return fmt.Errorf("camera %d already broadcasting", idx)
+		}
+	}
+
+	cp.mutex.Lock()
+	bp := &BroadcastProcess{cfg: BroadcastConfig{URL: url, Format: format}}
+	cp.broadcast = bp
+	cp.mutex.Unlock()
+
+	if err := m.launchBroadcast(idx, cp, bp); err != nil {
+		return err
+	}
+
+	m.persistBroadcastConfigs()
+	return nil
+}
+
+// launchBroadcast starts (or restarts) the broadcast child process for bp and
+// arms the monitor goroutine that reconnects with exponential backoff on
+// unexpected exit.
+func (m *Manager) launchBroadcast(idx int, cp *CameraProcess, bp *BroadcastProcess) error {
+	pipeline, err := buildBroadcastPipeline(cp.mainRTSPPath(), bp.cfg.URL, bp.cfg.Format)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, "gst-launch-1.0", "-e", pipeline)
+
+	logDir := "./logs"
+	os.MkdirAll(logDir, 0755)
+	stderrFile, _ := os.OpenFile(filepath.Join(logDir, fmt.Sprintf("cam%d_broadcast_stderr.log", idx)), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	cmd.Stdout = stderrFile
+	cmd.Stderr = &stderrTailWriter{bp: bp, max: broadcastStderrLines, next: stderrFile}
+
+	bp.mutex.Lock()
+	if err := cmd.Start(); err != nil {
+		bp.mutex.Unlock()
+		cancel()
+		return fmt.Errorf("failed to start broadcast for camera %d: %w", idx, err)
+	}
+	bp.cmd = cmd
+	bp.cancelFunc = cancel
+	bp.startedAt = time.Now()
+	bp.stopped = false
+	bp.mutex.Unlock()
+
+	go m.superviseBroadcast(idx, cp, bp, cmd)
+
+	log.Printf("Started broadcast for camera %d -> %s (%s)", idx, bp.cfg.URL, bp.cfg.Format)
+	return nil
+}
+
+// superviseBroadcast waits for the broadcast child to exit and, unless it was
+// stopped intentionally, reconnects with exponential backoff (1s, 2s, 4s,
+// ... capped at 30s).
+func (m *Manager) superviseBroadcast(idx int, cp *CameraProcess, bp *BroadcastProcess, cmd *exec.Cmd) {
+	err := cmd.Wait()
+
+	bp.mutex.Lock()
+	stopped := bp.stopped
+	bp.cmd = nil
+	if err != nil {
+		bp.lastErr = err.Error()
+	}
+	bp.mutex.Unlock()
+
+	if stopped {
+		log.Printf("broadcast for camera %d stopped", idx)
+		return
+	}
+
+	bp.mutex.Lock()
+	bp.reconnecting = true
+	bp.mutex.Unlock()
+
+	backoff := time.Second
+	for {
+		log.Printf("broadcast for camera %d exited unexpectedly (%v); reconnecting in %s", idx, err, backoff)
+		time.Sleep(backoff)
+
+		bp.mutex.Lock()
+		giveUp := bp.stopped
+		bp.mutex.Unlock()
+		if giveUp {
+			bp.mutex.Lock()
+			bp.reconnecting = false
+			bp.mutex.Unlock()
+			return
+		}
+
+		if launchErr := m.launchBroadcast(idx, cp, bp); launchErr == nil {
+			bp.mutex.Lock()
+			bp.reconnecting = false
+			bp.mutex.Unlock()
+			return // superviseBroadcast is re-armed by launchBroadcast for the new cmd
+		}
+
+		bp.mutex.Lock()
+		bp.restarts++
+		bp.mutex.Unlock()
 
-type Manager struct {
-	cameras map[int]*CameraProcess
-	lock    sync.Mutex
+		backoff *= 2
+		if backoff > 30*time.Second {
+			backoff = 30 * time.Second
+		}
+	}
 }
 
-func NewManager(cameraList []CameraInfo) *Manager {
-	m := &Manager{
-		cameras: make(map[int]*CameraProcess),
+// StopBroadcast tears down camera idx's broadcast pipeline. It does not touch
+// the capture pipeline or any WebRTC/RTSP consumers.
+func (m *Manager) StopBroadcast(idx int) error {
+	m.lock.Lock()
+	cp, ok := m.cameras[idx]
+	m.lock.Unlock()
+	if !ok {
+		return fmt.Errorf("camera %d not found", idx)
 	}
-	for i, cam := range cameraList {
-		rtspPath := fmt.Sprintf("rtsp://127.0.0.1:8554/cam%d", i)
-		m.cameras[i] = &CameraProcess{
-			info:     cam,
-			rtspPath: rtspPath,
+
+	cp.mutex.Lock()
+	bp := cp.broadcast
+	cp.broadcast = nil
+	cp.mutex.Unlock()
+
+	if bp == nil {
+		return fmt.Errorf("camera %d is not broadcasting", idx)
+	}
+
+	bp.mutex.Lock()
+	bp.stopped = true
+	if bp.cancelFunc != nil {
+		bp.cancelFunc()
+	}
+	cmd := bp.cmd
+	bp.mutex.Unlock()
+
+	if cmd != nil {
+		done := make(chan struct{})
+		go func() {
+			cmd.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			if cmd.Process != nil {
+				cmd.Process.Kill()
+			}
 		}
 	}
-	return m
+
+	m.persistBroadcastConfigs()
+	log.Printf("Stopped broadcast for camera %d", idx)
+	return nil
 }
 
-// StartCamera will spawn a gst-rtsp-server test-launch process for the given camera index.
-// It uses Jetson hardware encoder nvv4l2h264enc in the pipeline.
-func (m *Manager) StartCamera(idx int) error {
+// BroadcastStatus reports the current state of camera idx's broadcast,
+// including the last few stderr lines from the child process so operators can
+// diagnose RTMP/HLS handshake failures remotely.
+func (m *Manager) BroadcastStatus(idx int) (gin.H, error) {
 	m.lock.Lock()
 	cp, ok := m.cameras[idx]
 	m.lock.Unlock()
 	if !ok {
-		return fmt.Errorf("camera %d not found", idx)
+		return nil, fmt.Errorf("camera %d not found", idx)
 	}
 
 	cp.mutex.Lock()
-	defer cp.mutex.Unlock()
+	bp := cp.broadcast
+	cp.mutex.Unlock()
 
-	if cp.cmd != nil {
-		return fmt.Errorf("camera %d already started", idx)
+	if bp == nil {
+		return gin.H{"id": idx, "broadcasting": false}, nil
 	}
 
-	// Build GStreamer pipeline string. Adjust width/height/framerate/bitrate as needed.
-	// If you use CSI camera (nvarguscamerasrc), change the src element accordingly.
-	pipeline := fmt.Sprintf("( v4l2src device=%s ! video/x-raw,width=1280,height=720,framerate=30/1 ! nvvidconv ! 'video/x-raw(memory:NVMM),format=NV12' ! nvv4l2h264enc bitrate=2000000 ! h264parse ! rtph264pay name=pay0 pt=96 )", cp.info.Device)
+	bp.mutex.Lock()
+	defer bp.mutex.Unlock()
+	return gin.H{
+		"id":           idx,
+		"broadcasting": bp.cmd != nil,
+		"url":          bp.cfg.URL,
+		"format":       bp.cfg.Format,
+		"started":      bp.startedAt.Format(time.RFC3339),
+		"restarts":     bp.restarts,
+		"lastErr":      bp.lastErr,
+		"stderrTail":   bp.stderrTail,
+	}, nil
+}
+
+// ensureMJPEGHub starts cp's decode branch if it isn't already running and
+// returns the shared hub. Safe to call from multiple goroutines concurrently.
+func (m *Manager) ensureMJPEGHub(idx int, cp *CameraProcess) (*frameHub, error) {
+	cp.mutex.Lock()
+	if cp.mjpeg == nil {
+		cp.mjpeg = &frameHub{subscribers: make(map[chan []byte]struct{})}
+	}
+	hub := cp.mjpeg
+	cp.mutex.Unlock()
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	if hub.cmd != nil {
+		return hub, nil
+	}
+
+	pipeline := fmt.Sprintf("rtspsrc location=%s ! rtph264depay ! h264parse ! nvv4l2decoder ! nvvidconv ! video/x-raw,format=I420 ! jpegenc ! multipartmux boundary=frame ! fdsink fd=1", cp.mainRTSPPath())
 
-	// test-launch is the sample binary from gst-rtsp-server repo that runs a pipeline as RTSP server.
-	// If you don't have test-launch, install gst-rtsp-server or change this to another method.
 	ctx, cancel := context.WithCancel(context.Background())
-	cmd := exec.CommandContext(ctx, "test-launch", pipeline)
-	// send stdout/stderr to files for debugging
+	cmd := exec.CommandContext(ctx, "gst-launch-1.0", "-e", pipeline)
+
 	logDir := "./logs"
 	os.MkdirAll(logDir, 0755)
-	stdoutFile, _ := os.OpenFile(filepath.Join(logDir, fmt.Sprintf("cam%d_stdout.log", idx)), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	stderrFile, _ := os.OpenFile(filepath.Join(logDir, fmt.Sprintf("cam%d_stderr.log", idx)), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	cmd.Stdout = stdoutFile
+	stderrFile, _ := os.OpenFile(filepath.Join(logDir, fmt.Sprintf("cam%d_mjpeg_stderr.log", idx)), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	cmd.Stderr = stderrFile
 
-	// Start the process
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("camera %d: failed to open mjpeg stdout pipe: %w", idx, err)
+	}
+
 	if err := cmd.Start(); err != nil {
 		cancel()
-		return fmt.Errorf("failed to start test-launch for camera %d: %w", idx, err)
+		return nil, fmt.Errorf("camera %d: failed to start mjpeg pipeline: %w", idx, err)
 	}
 
-	cp.cmd = cmd
-	cp.cancelFunc = cancel
-	cp.startedAt = time.Now()
-	cp.restarts = 0
+	hub.generation++
+	gen := hub.generation
+	hub.cmd = cmd
+	hub.cancelFunc = cancel
+	done := make(chan struct{})
+	hub.done = done
 
-	// Monitor in goroutine: wait for exit and cleanup
-	go func(index int, p *CameraProcess, stdout, stderr *os.File) {
-		err := cmd.Wait()
-		stdout.Close()
-		stderr.Close()
-		p.mutex.Lock()
-		p.cmd = nil
-		if p.cancelFunc != nil {
-			p.cancelFunc()
-			p.cancelFunc = nil
-		}
-		p.mutex.Unlock()
+	go m.pumpMJPEG(idx, hub, stdout, cmd, gen, done)
+
+	log.Printf("camera %d: started mjpeg decode branch", idx)
+	return hub, nil
+}
+
+// pumpMJPEG reads the multipart JPEG stream produced by the decode branch and
+// fans each frame out to every current subscriber, dropping frames for slow
+// consumers instead of blocking the pump. gen is the hub generation this
+// pump's cmd was started for; cleanup only tears down hub state if the hub
+// is still on that same generation, so a pump whose process was already
+// superseded by a newer ensureMJPEGHub call doesn't clobber it.
+//
+// pumpMJPEG itself reaps cmd via cmd.Wait() before touching hub state, so the
+// child is always reaped whether the stream ended because the process
+// crashed or because unsubscribe canceled it - unlike relying on
+// unsubscribe's own cmd.Wait(), which only fires on the intentional-stop path
+// and, by the time a crash has already cleared hub.cmd here, never runs at
+// all. done is closed last so a waiting unsubscribe knows cleanup finished.
+func (m *Manager) pumpMJPEG(idx int, hub *frameHub, stdout io.ReadCloser, cmd *exec.Cmd, gen uint64, done chan struct{}) {
+	reader := multipart.NewReader(stdout, "frame")
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			break
+		}
+		data, err := io.ReadAll(part)
 		if err != nil {
-			log.Printf("camera %d process exited with error: %v (logs: %s, %s)", index, err, stdout.Name(), stderr.Name())
-		} else {
-			log.Printf("camera %d process exited cleanly", index)
+			break
+		}
+
+		hub.mutex.Lock()
+		for ch := range hub.subscribers {
+			select {
+			case ch <- data:
+			default: // slow consumer; drop this frame rather than block the pump
+			}
 		}
-	}(idx, cp, stdoutFile, stderrFile)
+		hub.mutex.Unlock()
+	}
 
-	// Small sleep to let RTSP server up (could poll the RTSP URL in production)
-	time.Sleep(400 * time.Millisecond)
+	if err := cmd.Wait(); err != nil {
+		log.Printf("camera %d: mjpeg decode branch exited with error: %v", idx, err)
+	} else {
+		log.Printf("camera %d: mjpeg decode branch stopped", idx)
+	}
 
-	log.Printf("Started camera %d -> %s (pipeline: %s)", idx, cp.rtspPath, pipeline)
-	return nil
+	hub.mutex.Lock()
+	if hub.generation == gen {
+		hub.cmd = nil
+		if hub.cancelFunc != nil {
+			hub.cancelFunc()
+			hub.cancelFunc = nil
+		}
+		hub.done = nil
+		for ch := range hub.subscribers {
+			close(ch)
+			delete(hub.subscribers, ch)
+		}
+	}
+	hub.mutex.Unlock()
+	close(done)
 }
 
-func (m *Manager) StopCamera(idx int) error {
+// subscribeMJPEG registers a new consumer of camera idx's decoded JPEG
+// stream, starting the shared decode branch on demand. The returned func
+// unsubscribes and, once the last subscriber leaves, stops the branch.
+func (m *Manager) subscribeMJPEG(idx int) (<-chan []byte, func(), error) {
 	m.lock.Lock()
 	cp, ok := m.cameras[idx]
 	m.lock.Unlock()
 	if !ok {
-		return fmt.Errorf("camera %d not found", idx)
+		return nil, nil, fmt.Errorf("camera %d not found", idx)
+	}
+
+	hub, err := m.ensureMJPEGHub(idx, cp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan []byte, 2)
+	hub.mutex.Lock()
+	hub.subscribers[ch] = struct{}{}
+	hub.mutex.Unlock()
+
+	unsubscribe := func() {
+		hub.mutex.Lock()
+		delete(hub.subscribers, ch)
+		lastSubscriber := len(hub.subscribers) == 0
+		cmd := hub.cmd
+		cancel := hub.cancelFunc
+		done := hub.done
+		hub.mutex.Unlock()
+
+		if lastSubscriber && cancel != nil {
+			cancel()
+			// pumpMJPEG reaps cmd and clears hub state itself; just wait for
+			// it to finish, falling back to a hard kill if it doesn't in time.
+			if done != nil {
+				select {
+				case <-done:
+				case <-time.After(2 * time.Second):
+					if cmd != nil && cmd.Process != nil {
+						cmd.Process.Kill()
+					}
+				}
+			}
+		}
+	}
+
+	return ch, unsubscribe, nil
+}
+
+// SnapshotJPEG grabs a single JPEG frame from camera idx's decode branch,
+// starting it on demand for the low-power/ML-preview use case that doesn't
+// want a full MJPEG player.
+func (m *Manager) SnapshotJPEG(idx int, timeout time.Duration) ([]byte, error) {
+	frames, unsubscribe, err := m.subscribeMJPEG(idx)
+	if err != nil {
+		return nil, err
 	}
+	defer unsubscribe()
 
+	select {
+	case frame, ok := <-frames:
+		if !ok {
+			return nil, fmt.Errorf("camera %d: mjpeg stream closed before a frame arrived", idx)
+		}
+		return frame, nil
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("camera %d: timed out waiting for a frame", idx)
+	}
+}
+
+// ensureRTPHub starts cp's RTP passthrough branch if it isn't already
+// running and returns the shared hub. The branch only depays/repays H.264
+// (rtph264depay ! h264parse ! rtph264pay) so downstream consumers never pay
+// for a decode/re-encode.
+func (m *Manager) ensureRTPHub(idx int, cp *CameraProcess) (*RTPHub, error) {
 	cp.mutex.Lock()
-	defer cp.mutex.Unlock()
+	if cp.rtp == nil {
+		cp.rtp = &RTPHub{subscribers: make(map[chan *rtp.Packet]struct{}), payloadType: 96}
+	}
+	hub := cp.rtp
+	cp.mutex.Unlock()
 
-	if cp.cmd == nil {
-		return fmt.Errorf("camera %d not running", idx)
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	if hub.cmd != nil {
+		return hub, nil
 	}
 
-	// Graceful shutdown via cancel func
-	if cp.cancelFunc != nil {
-		cp.cancelFunc()
+	udpConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 0})
+	if err != nil {
+		return nil, fmt.Errorf("camera %d: failed to allocate rtp passthrough port: %w", idx, err)
 	}
+	port := udpConn.LocalAddr().(*net.UDPAddr).Port
 
-	// give it a short time, then kill
+	pipeline := fmt.Sprintf("rtspsrc location=%s ! rtph264depay ! h264parse config-interval=1 ! rtph264pay pt=%d config-interval=1 ! udpsink host=127.0.0.1 port=%d sync=false", cp.mainRTSPPath(), hub.payloadType, port)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, "gst-launch-1.0", "-e", pipeline)
+
+	logDir := "./logs"
+	os.MkdirAll(logDir, 0755)
+	stderrFile, _ := os.OpenFile(filepath.Join(logDir, fmt.Sprintf("cam%d_rtp_stderr.log", idx)), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	cmd.Stderr = stderrFile
+
+	if err := cmd.Start(); err != nil {
+		udpConn.Close()
+		cancel()
+		return nil, fmt.Errorf("camera %d: failed to start rtp passthrough pipeline: %w", idx, err)
+	}
+
+	hub.generation++
+	gen := hub.generation
+	hub.cmd = cmd
+	hub.cancelFunc = cancel
+	hub.udpConn = udpConn
 	done := make(chan struct{})
+	hub.done = done
+
+	go m.pumpRTP(idx, hub, udpConn, cmd, gen, done)
+
+	log.Printf("camera %d: started rtp passthrough branch on udp port %d", idx, port)
+	return hub, nil
+}
+
+// pumpRTP reads RTP packets off the loopback UDP socket the pipeline writes
+// to and fans each one out to every current subscriber, dropping packets for
+// slow consumers instead of blocking the read loop. gen is the hub
+// generation this pump's cmd/udpConn were started for; cleanup only tears
+// down hub state if the hub is still on that same generation, so a pump
+// whose process was already superseded by a newer ensureRTPHub call doesn't
+// clobber it.
+//
+// A dead remote sender never makes conn.Read return an error - UDP has no
+// concept of a closed peer - so conn.Read alone can't detect a crashed
+// pipeline. A watcher goroutine calls cmd.Wait() concurrently and closes conn
+// the moment the child exits for any reason, which unblocks the read loop
+// below and lets pumpRTP reap the child and clear hub state the same way
+// regardless of whether the crash, the read loop, or unsubscribe's cancel()
+// was what actually ended the branch.
+func (m *Manager) pumpRTP(idx int, hub *RTPHub, conn *net.UDPConn, cmd *exec.Cmd, gen uint64, done chan struct{}) {
+	exited := make(chan error, 1)
 	go func() {
-		cp.cmd.Wait()
-		close(done)
+		err := cmd.Wait()
+		conn.Close()
+		exited <- err
 	}()
 
-	select {
-	case <-done:
-	case <-time.After(2 * time.Second):
-		if cp.cmd.Process != nil {
-			cp.cmd.Process.Kill()
+	buf := make([]byte, 2048)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			break
+		}
+		pkt := &rtp.Packet{}
+		if err := pkt.Unmarshal(buf[:n]); err != nil {
+			continue
+		}
+
+		hub.mutex.Lock()
+		for ch := range hub.subscribers {
+			select {
+			case ch <- pkt:
+			default: // slow consumer; drop this packet rather than block the read loop
+			}
 		}
+		hub.mutex.Unlock()
 	}
 
-	cp.cmd = nil
-	cp.cancelFunc = nil
-	log.Printf("Stopped camera %d", idx)
-	return nil
+	if err := <-exited; err != nil {
+		log.Printf("camera %d: rtp passthrough branch exited with error: %v", idx, err)
+	} else {
+		log.Printf("camera %d: rtp passthrough branch stopped", idx)
+	}
+
+	hub.mutex.Lock()
+	if hub.generation == gen {
+		hub.cmd = nil
+		if hub.cancelFunc != nil {
+			hub.cancelFunc()
+			hub.cancelFunc = nil
+		}
+		hub.udpConn = nil
+		hub.done = nil
+		for ch := range hub.subscribers {
+			close(ch)
+			delete(hub.subscribers, ch)
+		}
+	}
+	hub.mutex.Unlock()
+	close(done)
+}
+
+// Subscribe registers a new RTP passthrough consumer for camera idx, starting
+// the shared branch on demand. The returned func unsubscribes and, once the
+// last subscriber leaves, stops the branch.
+func (m *Manager) Subscribe(idx int) (<-chan *rtp.Packet, func(), error) {
+	m.lock.Lock()
+	cp, ok := m.cameras[idx]
+	m.lock.Unlock()
+	if !ok {
+		return nil, nil, fmt.Errorf("camera %d not found", idx)
+	}
+
+	hub, err := m.ensureRTPHub(idx, cp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *rtp.Packet, 32)
+	hub.mutex.Lock()
+	hub.subscribers[ch] = struct{}{}
+	hub.mutex.Unlock()
+
+	unsubscribe := func() {
+		hub.mutex.Lock()
+		delete(hub.subscribers, ch)
+		lastSubscriber := len(hub.subscribers) == 0
+		cmd := hub.cmd
+		cancel := hub.cancelFunc
+		conn := hub.udpConn
+		done := hub.done
+		hub.mutex.Unlock()
+
+		if lastSubscriber {
+			if cancel != nil {
+				cancel()
+			}
+			if conn != nil {
+				conn.Close()
+			}
+			// pumpRTP reaps cmd and clears hub state itself; just wait for
+			// it to finish, falling back to a hard kill if it doesn't in time.
+			if done != nil {
+				select {
+				case <-done:
+				case <-time.After(2 * time.Second):
+					if cmd != nil && cmd.Process != nil {
+						cmd.Process.Kill()
+					}
+				}
+			}
+		}
+	}
+
+	return ch, unsubscribe, nil
+}
+
+// RTPSDP describes camera idx's passthrough branch as a minimal SDP answer so
+// a WebSocket client of /rtp/:id knows the payload type before it starts
+// parsing packets.
+func (m *Manager) RTPSDP(idx int) (string, error) {
+	m.lock.Lock()
+	cp, ok := m.cameras[idx]
+	m.lock.Unlock()
+	if !ok {
+		return "", fmt.Errorf("camera %d not found", idx)
+	}
+
+	cp.mutex.Lock()
+	hub := cp.rtp
+	cp.mutex.Unlock()
+	if hub == nil {
+		return "", fmt.Errorf("camera %d: rtp passthrough not started", idx)
+	}
+
+	return fmt.Sprintf(
+		"v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=cam%d\r\nt=0 0\r\nm=video 0 RTP/AVP %d\r\na=rtpmap:%d H264/90000\r\na=control:trackID=0\r\n",
+		idx, hub.payloadType, hub.payloadType,
+	), nil
 }
 
 func (m *Manager) Status() []gin.H {
@@ -169,17 +1622,27 @@ func (m *Manager) Status() []gin.H {
 	defer m.lock.Unlock()
 	out := make([]gin.H, 0, len(m.cameras))
 	for i, cp := range m.cameras {
-		cp.mutex.Lock()
-		running := cp.cmd != nil
-		startAt := cp.startedAt
-		cp.mutex.Unlock()
+		streams := make([]gin.H, 0, len(cp.profileOrder))
+		for _, name := range cp.profileOrder {
+			pp := cp.profiles[name]
+			pp.mutex.Lock()
+			streams = append(streams, gin.H{
+				"profile":      name,
+				"rtsp":         pp.rtspPath,
+				"running":      pp.cmd != nil,
+				"started":      pp.startedAt.Format(time.RFC3339),
+				"health":       pp.health,
+				"restarts":     pp.restarts,
+				"lastExitCode": pp.lastExitCode,
+				"lastExitAt":   pp.lastExitAt.Format(time.RFC3339),
+			})
+			pp.mutex.Unlock()
+		}
 		out = append(out, gin.H{
-			"id":       i,
-			"device":   cp.info.Device,
-			"product":  cp.info.Product,
-			"rtsp":     cp.rtspPath,
-			"running":  running,
-			"started":  startAt.Format(time.RFC3339),
+			"id":      i,
+			"device":  cp.info.Device,
+			"product": cp.info.Product,
+			"streams": streams,
 		})
 	}
 	return out
@@ -202,6 +1665,9 @@ func main() {
 		}
 	}
 
+	// Restore any broadcast destinations persisted from a previous run.
+	manager.LoadBroadcastConfigs()
+
 	// Setup HTTP control server
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
@@ -221,7 +1687,18 @@ func main() {
 			c.JSON(500, gin.H{"error": err.Error()})
 			return
 		}
-		c.JSON(200, gin.H{"status": "started", "id": id, "rtsp": manager.cameras[id].rtspPath})
+		c.JSON(200, gin.H{"status": "started", "id": id})
+	})
+
+	r.POST("/start/:id/:profile", func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, _ := strconv.Atoi(idStr)
+		profile := c.Param("profile")
+		if err := manager.StartCameraProfile(id, profile); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"status": "started", "id": id, "profile": profile})
 	})
 
 	r.POST("/stop/:id", func(c *gin.Context) {
@@ -234,6 +1711,184 @@ func main() {
 		c.JSON(200, gin.H{"status": "stopped", "id": id})
 	})
 
+	r.POST("/stop/:id/:profile", func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, _ := strconv.Atoi(idStr)
+		profile := c.Param("profile")
+		if err := manager.StopCameraProfile(id, profile); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"status": "stopped", "id": id, "profile": profile})
+	})
+
+	r.GET("/health/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, _ := strconv.Atoi(idStr)
+		status, err := manager.HealthStatus(id)
+		if err != nil {
+			c.JSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, status)
+	})
+
+	r.GET("/health/:id/:profile", func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, _ := strconv.Atoi(idStr)
+		profile := c.Param("profile")
+		status, err := manager.ProfileHealthStatus(id, profile)
+		if err != nil {
+			c.JSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, status)
+	})
+
+	r.POST("/disable/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, _ := strconv.Atoi(idStr)
+		if err := manager.DisableCamera(id); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"status": "disabled", "id": id})
+	})
+
+	r.POST("/disable/:id/:profile", func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, _ := strconv.Atoi(idStr)
+		profile := c.Param("profile")
+		if err := manager.DisableCameraProfile(id, profile); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"status": "disabled", "id": id, "profile": profile})
+	})
+
+	r.POST("/broadcast/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, _ := strconv.Atoi(idStr)
+		var req struct {
+			URL    string `json:"url"`
+			Format string `json:"format"`
+		}
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		if err := manager.StartBroadcast(id, req.URL, req.Format); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"status": "broadcasting", "id": id, "url": req.URL, "format": req.Format})
+	})
+
+	r.POST("/broadcast/:id/stop", func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, _ := strconv.Atoi(idStr)
+		if err := manager.StopBroadcast(id); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"status": "stopped", "id": id})
+	})
+
+	r.GET("/broadcast/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, _ := strconv.Atoi(idStr)
+		status, err := manager.BroadcastStatus(id)
+		if err != nil {
+			c.JSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, status)
+	})
+
+	r.GET("/snapshot/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, _ := strconv.Atoi(idStr)
+		frame, err := manager.SnapshotJPEG(id, 5*time.Second)
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(200, "image/jpeg", frame)
+	})
+
+	r.GET("/mjpeg/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, _ := strconv.Atoi(idStr)
+		frames, unsubscribe, err := manager.subscribeMJPEG(id)
+		if err != nil {
+			c.JSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		defer unsubscribe()
+
+		c.Writer.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary=frame")
+		c.Writer.WriteHeader(http.StatusOK)
+		flusher, _ := c.Writer.(http.Flusher)
+
+		for {
+			select {
+			case frame, ok := <-frames:
+				if !ok {
+					return
+				}
+				fmt.Fprintf(c.Writer, "--frame\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", len(frame))
+				c.Writer.Write(frame)
+				c.Writer.Write([]byte("\r\n"))
+				if flusher != nil {
+					flusher.Flush()
+				}
+			case <-c.Request.Context().Done():
+				return
+			}
+		}
+	})
+
+	r.GET("/rtp/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, _ := strconv.Atoi(idStr)
+		packets, unsubscribe, err := manager.Subscribe(id)
+		if err != nil {
+			c.JSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		defer unsubscribe()
+
+		sdp, err := manager.RTPSDP(id)
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+
+		conn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			log.Printf("rtp/%d: websocket upgrade failed: %v", id, err)
+			return
+		}
+		defer conn.Close()
+
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(sdp)); err != nil {
+			return
+		}
+
+		for pkt := range packets {
+			raw, err := pkt.Marshal()
+			if err != nil {
+				continue
+			}
+			frame := make([]byte, 2+len(raw))
+			binary.BigEndian.PutUint16(frame, uint16(len(raw)))
+			copy(frame[2:], raw)
+			if err := conn.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+				return
+			}
+		}
+	})
+
 	r.GET("/rtsp/:id", func(c *gin.Context) {
 		idStr := c.Param("id")
 		id, _ := strconv.Atoi(idStr)
@@ -244,7 +1899,11 @@ func main() {
 			c.JSON(404, gin.H{"error": "not found"})
 			return
 		}
-		c.JSON(200, gin.H{"rtsp": cp.rtspPath})
+		streams := make(gin.H, len(cp.profileOrder))
+		for _, name := range cp.profileOrder {
+			streams[name] = cp.profiles[name].rtspPath
+		}
+		c.JSON(200, gin.H{"streams": streams})
 	})
 
 	// start HTTP server
@@ -266,8 +1925,9 @@ func main() {
 	<-sigs
 	log.Println("shutting down...")
 
-	// Stop cameras
+	// Stop cameras and any broadcasts riding on them
 	for i := range cameraList {
+		_ = manager.StopBroadcast(i)
 		_ = manager.StopCamera(i)
 	}
 
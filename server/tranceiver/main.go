@@ -1,61 +1,486 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
+	"log"
+	"net"
 	"net/http"
-	"strconv"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
-func main() {
-	// Ports tujuan untuk forwarding
-	targetPorts := []int{1000, 2000, 3000, 4000, 5000, 6000, 7000, 8000}
-	client := &http.Client{Timeout: 5 * time.Second}
-
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		// Mencoba request ke setiap port secara berurutan
-		for _, port := range targetPorts {
-			targetURL := "http://localhost:" + strconv.Itoa(port) + r.URL.Path
-			if r.URL.RawQuery != "" {
-				targetURL += "?" + r.URL.RawQuery
-			}
+// defaultConfigPath is read on startup; override with an argument, e.g.
+// `tranceiver ./router.json`.
+const defaultConfigPath = "./router.json"
 
-			// Membuat request baru
-			proxyReq, err := http.NewRequest(r.Method, targetURL, r.Body)
-			if err != nil {
-				continue // Coba port berikutnya jika gagal
-			}
+// defaultMaxBufferedBody caps how much of a request body we'll buffer in
+// memory to make it replayable across backend retries.
+const defaultMaxBufferedBody = 1 << 20 // 1MB
 
-			// Menyalin header dari request asli
-			proxyReq.Header = make(http.Header)
-			for k, v := range r.Header {
-				proxyReq.Header[k] = v
-			}
+// defaultHealthCheckPeriod is used when a route config doesn't specify one.
+const defaultHealthCheckPeriod = 5 * time.Second
+
+// BackendConfig is one candidate target for a route.
+type BackendConfig struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// RouteConfig maps a path prefix to the backends allowed to serve it.
+type RouteConfig struct {
+	Prefix   string          `json:"prefix"`
+	Backends []BackendConfig `json:"backends"`
+}
+
+// Config is the on-disk (JSON) routing table.
+type Config struct {
+	Routes               []RouteConfig `json:"routes"`
+	HealthCheckPath      string        `json:"healthCheckPath"`
+	HealthCheckPeriod    string        `json:"healthCheckPeriod"` // duration string, e.g. "5s"
+	MaxBufferedBodyBytes int64         `json:"maxBufferedBodyBytes"`
+}
+
+// backendMetrics holds the Prometheus-style counters for one backend.
+type backendMetrics struct {
+	requests     atomic.Uint64
+	retries      atomic.Uint64
+	serverErrors atomic.Uint64
+	latencyNsSum atomic.Uint64
+	latencyCount atomic.Uint64
+}
+
+// backend is one proxy target behind a route.
+type backend struct {
+	name    string
+	url     *url.URL
+	proxy   *httputil.ReverseProxy
+	healthy atomic.Bool
+	metrics backendMetrics
+}
+
+// route is a path prefix plus the backends allowed to serve it.
+type route struct {
+	prefix   string
+	backends []*backend
+}
+
+func (rte *route) healthyBackends() []*backend {
+	out := make([]*backend, 0, len(rte.backends))
+	for _, b := range rte.backends {
+		if b.healthy.Load() {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// Router replaces the old sequential port scan: it dispatches by path prefix
+// to only the backends that active health checks currently consider up, and
+// retries a request against the next healthy backend when one returns 5xx
+// (as long as the body was small enough to buffer for replay).
+type Router struct {
+	routes          []*route
+	client          *http.Client
+	maxBufferedBody int64
+	healthCheckPath string
+}
+
+// LoadConfig reads and parses the JSON routing table at path.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// defaultConfig reproduces the original hardcoded port list as a route
+// config, so the router still runs with zero setup if no config file exists.
+func defaultConfig() *Config {
+	backends := make([]BackendConfig, 0, 8)
+	for _, port := range []int{1000, 2000, 3000, 4000, 5000, 6000, 7000, 8000} {
+		backends = append(backends, BackendConfig{
+			Name: fmt.Sprintf("backend-%d", port),
+			URL:  fmt.Sprintf("http://localhost:%d", port),
+		})
+	}
+	return &Config{
+		Routes:            []RouteConfig{{Prefix: "/", Backends: backends}},
+		HealthCheckPeriod: "5s",
+	}
+}
+
+// NewRouter builds a Router from cfg and starts each backend's health check
+// loop in the background.
+func NewRouter(cfg *Config) (*Router, error) {
+	healthPeriod := defaultHealthCheckPeriod
+	if cfg.HealthCheckPeriod != "" {
+		d, err := time.ParseDuration(cfg.HealthCheckPeriod)
+		if err != nil {
+			return nil, fmt.Errorf("invalid healthCheckPeriod %q: %w", cfg.HealthCheckPeriod, err)
+		}
+		healthPeriod = d
+	}
 
-			// Mengirim request
-			resp, err := client.Do(proxyReq)
+	maxBody := int64(defaultMaxBufferedBody)
+	if cfg.MaxBufferedBodyBytes > 0 {
+		maxBody = cfg.MaxBufferedBodyBytes
+	}
+
+	healthPath := cfg.HealthCheckPath
+	if healthPath == "" {
+		healthPath = "/"
+	}
+
+	rt := &Router{
+		client:          &http.Client{Timeout: 5 * time.Second},
+		maxBufferedBody: maxBody,
+		healthCheckPath: healthPath,
+	}
+
+	for _, rc := range cfg.Routes {
+		if rc.Prefix == "" {
+			return nil, fmt.Errorf("route with empty prefix")
+		}
+		rte := &route{prefix: rc.Prefix}
+		for _, bc := range rc.Backends {
+			target, err := url.Parse(bc.URL)
 			if err != nil {
-				continue // Coba port berikutnya jika gagal
+				return nil, fmt.Errorf("route %s: invalid backend url %q: %w", rc.Prefix, bc.URL, err)
 			}
-			defer resp.Body.Close()
 
-			// Jika response bukan 404, kembalikan response tersebut
-			if resp.StatusCode != http.StatusNotFound {
-				// Menyalin header dari response
-				for k, v := range resp.Header {
-					w.Header()[k] = v
+			b := &backend{name: bc.Name, url: target}
+			b.healthy.Store(true) // optimistic until the first health check lands
+
+			proxy := httputil.NewSingleHostReverseProxy(target)
+			baseDirector := proxy.Director
+			prefix := rc.Prefix
+			proxy.Director = func(req *http.Request) {
+				req.URL.Path = strings.TrimPrefix(req.URL.Path, prefix)
+				if !strings.HasPrefix(req.URL.Path, "/") {
+					req.URL.Path = "/" + req.URL.Path
 				}
-				w.WriteHeader(resp.StatusCode)
-				io.Copy(w, resp.Body)
-				return
+				baseDirector(req)
 			}
+			b.proxy = proxy
+
+			rte.backends = append(rte.backends, b)
+		}
+		rt.routes = append(rt.routes, rte)
+	}
+
+	for _, rte := range rt.routes {
+		for _, b := range rte.backends {
+			go rt.healthCheckLoop(b, healthPeriod)
 		}
+	}
+
+	return rt, nil
+}
+
+// healthCheckLoop probes b immediately, then again every period, forever.
+func (rt *Router) healthCheckLoop(b *backend, period time.Duration) {
+	rt.probeBackend(b)
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for range ticker.C {
+		rt.probeBackend(b)
+	}
+}
+
+// probeBackend issues a HEAD request to the backend's health path and
+// records whether it should be considered up.
+func (rt *Router) probeBackend(b *backend) {
+	target := strings.TrimSuffix(b.url.String(), "/") + rt.healthCheckPath
+	req, err := http.NewRequest(http.MethodHead, target, nil)
+	if err != nil {
+		b.healthy.Store(false)
+		return
+	}
+
+	resp, err := rt.client.Do(req)
+	if err != nil {
+		b.healthy.Store(false)
+		return
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	b.healthy.Store(resp.StatusCode < http.StatusInternalServerError)
+}
+
+// matchRoute finds the longest prefix match for path, so a more specific
+// route (e.g. /cam/sub) wins over a broader one (e.g. /cam).
+func (rt *Router) matchRoute(path string) *route {
+	var best *route
+	for _, rte := range rt.routes {
+		if strings.HasPrefix(path, rte.prefix) && (best == nil || len(rte.prefix) > len(best.prefix)) {
+			best = rte
+		}
+	}
+	return best
+}
+
+// ServeHTTP dispatches to the matching route's healthy backends, buffering
+// the request body (up to maxBufferedBody) so it can be replayed against the
+// next backend if the current one returns a 5xx. The response itself is
+// never buffered: each attempt proxies straight into w through a
+// retryableResponseWriter, which only defers the decision of whether to
+// commit or retry until it sees the backend's status, so streaming
+// responses (e.g. /mjpeg's multipart stream) and protocol upgrades (e.g.
+// /rtp's websocket) still proxy correctly.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rte := rt.matchRoute(r.URL.Path)
+	if rte == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	backends := rte.healthyBackends()
+	if len(backends) == 0 {
+		http.Error(w, "no healthy backend available for this route", http.StatusServiceUnavailable)
+		return
+	}
+
+	bodyBytes, replayable, err := rt.bufferBody(r)
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusInternalServerError)
+		return
+	}
+	if !replayable {
+		backends = backends[:1] // body too large to buffer safely; try exactly one backend
+	}
+
+	for i, b := range backends {
+		attempt := r.Clone(r.Context())
+		if replayable {
+			attempt.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+			attempt.ContentLength = int64(len(bodyBytes))
+		}
+
+		last := i == len(backends)-1
+		rrw := newRetryableResponseWriter(w, !last)
+
+		start := time.Now()
+		b.proxy.ServeHTTP(rrw, attempt)
+		b.metrics.requests.Add(1)
+		b.metrics.latencyNsSum.Add(uint64(time.Since(start).Nanoseconds()))
+		b.metrics.latencyCount.Add(1)
+
+		if rrw.status >= http.StatusInternalServerError {
+			b.metrics.serverErrors.Add(1)
+		}
+		if rrw.discard {
+			b.metrics.retries.Add(1)
+			continue
+		}
+
+		return
+	}
+}
+
+// retryableResponseWriter wraps the real http.ResponseWriter so ServeHTTP
+// can inspect a backend attempt's status before deciding whether to retry,
+// without buffering the body: a non-5xx status (or the last attempt,
+// regardless of status) commits immediately, after which every Write,
+// Flush, and Hijack call passes straight through to the real writer. Only
+// the header map is held back pending that decision, since committing
+// headers from an attempt that ends up being retried would leak into the
+// next attempt's response.
+type retryableResponseWriter struct {
+	real      http.ResponseWriter
+	retryable bool // if true, a 5xx status triggers a retry instead of committing
+	header    http.Header
+	committed bool
+	discard   bool // true once this attempt's status was retryable; further writes are dropped
+	status    int
+}
+
+func newRetryableResponseWriter(real http.ResponseWriter, retryable bool) *retryableResponseWriter {
+	return &retryableResponseWriter{real: real, retryable: retryable, header: make(http.Header)}
+}
+
+func (w *retryableResponseWriter) Header() http.Header {
+	if w.committed {
+		return w.real.Header()
+	}
+	return w.header
+}
+
+func (w *retryableResponseWriter) WriteHeader(status int) {
+	if w.committed || w.discard {
+		return
+	}
+	if w.retryable && status >= http.StatusInternalServerError {
+		w.status = status
+		w.discard = true
+		return
+	}
+
+	w.status = status
+	w.committed = true
+	realHeader := w.real.Header()
+	for k, vv := range w.header {
+		realHeader[k] = vv
+	}
+	w.real.WriteHeader(status)
+}
+
+func (w *retryableResponseWriter) Write(p []byte) (int, error) {
+	if w.discard {
+		return len(p), nil // drop the body of an attempt that's being retried
+	}
+	if !w.committed {
+		w.WriteHeader(http.StatusOK) // mirror http.ResponseWriter's implicit 200 on first Write
+	}
+	return w.real.Write(p)
+}
+
+// Flush lets streaming responses (e.g. /mjpeg's multipart frames) reach the
+// client as they're written instead of waiting for the handler to return.
+func (w *retryableResponseWriter) Flush() {
+	if w.discard {
+		return
+	}
+	if f, ok := w.real.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack lets the reverse proxy hand a protocol upgrade (e.g. /rtp's
+// websocket, a 101 response) straight to the real connection.
+// httputil.ReverseProxy requires this for any backend response with
+// StatusSwitchingProtocols.
+func (w *retryableResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.real.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("underlying ResponseWriter does not support hijacking")
+	}
+	w.committed = true
+	return hj.Hijack()
+}
+
+// bufferBody reads r's body into memory (up to maxBufferedBody+1 bytes) so it
+// can be replayed against multiple backends. replayable is false if the body
+// was too large to buffer, in which case the original, now-partially-drained
+// body is reattached so the single attempt still sees the full payload.
+func (rt *Router) bufferBody(r *http.Request) (body []byte, replayable bool, err error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, true, nil
+	}
+
+	limited := io.LimitReader(r.Body, rt.maxBufferedBody+1)
+	data, err := io.ReadAll(limited)
+	if err != nil {
+		r.Body.Close()
+		return nil, false, err
+	}
+
+	if int64(len(data)) > rt.maxBufferedBody {
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), r.Body))
+		return nil, false, nil
+	}
+
+	r.Body.Close()
+	return data, true, nil
+}
+
+type routeStatus struct {
+	Prefix   string          `json:"prefix"`
+	Backends []backendStatus `json:"backends"`
+}
+
+type backendStatus struct {
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+	Healthy bool   `json:"healthy"`
+}
+
+// RoutesHandler serves the current routing table and backend health, e.g.
+// for a dashboard or to debug why traffic isn't reaching a backend.
+func (rt *Router) RoutesHandler(w http.ResponseWriter, r *http.Request) {
+	out := make([]routeStatus, 0, len(rt.routes))
+	for _, rte := range rt.routes {
+		rs := routeStatus{Prefix: rte.prefix}
+		for _, b := range rte.backends {
+			rs.Backends = append(rs.Backends, backendStatus{
+				Name:    b.name,
+				URL:     b.url.String(),
+				Healthy: b.healthy.Load(),
+			})
+		}
+		out = append(out, rs)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
+
+// MetricsHandler exposes per-backend counters in the Prometheus text
+// exposition format.
+func (rt *Router) MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	for _, rte := range rt.routes {
+		for _, b := range rte.backends {
+			count := b.metrics.latencyCount.Load()
+			var avgMs float64
+			if count > 0 {
+				avgMs = float64(b.metrics.latencyNsSum.Load()) / float64(count) / 1e6
+			}
+			fmt.Fprintf(w, "tranceiver_requests_total{backend=%q} %d\n", b.name, b.metrics.requests.Load())
+			fmt.Fprintf(w, "tranceiver_retries_total{backend=%q} %d\n", b.name, b.metrics.retries.Load())
+			fmt.Fprintf(w, "tranceiver_5xx_total{backend=%q} %d\n", b.name, b.metrics.serverErrors.Load())
+			fmt.Fprintf(w, "tranceiver_latency_ms_avg{backend=%q} %f\n", b.name, avgMs)
+			fmt.Fprintf(w, "tranceiver_backend_healthy{backend=%q} %d\n", b.name, boolToInt(b.healthy.Load()))
+		}
+	}
+}
+
+func boolToInt(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	// Config path bisa dioverride lewat argumen pertama.
+	configPath := defaultConfigPath
+	if len(os.Args) > 1 {
+		configPath = os.Args[1]
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		log.Printf("tranceiver: no usable config at %s (%v); falling back to built-in defaults", configPath, err)
+		cfg = defaultConfig()
+	}
+
+	rt, err := NewRouter(cfg)
+	if err != nil {
+		log.Fatalf("tranceiver: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/routes", rt.RoutesHandler)
+	mux.HandleFunc("/metrics", rt.MetricsHandler)
+	mux.Handle("/", rt)
 
-		// Jika semua port mengembalikan 404
-		w.WriteHeader(http.StatusNotFound)
-		w.Write([]byte("404 - All endpoints returned not found"))
-	})
+	log.Printf("tranceiver router listening on :9000 (config: %s)", configPath)
 
-	// Menjalankan server pada port 9000
-	http.ListenAndServe(":9000", nil)
-}
\ No newline at end of file
+	srv := &http.Server{Addr: ":9000", Handler: mux}
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatalf("tranceiver: %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// newTestBackend spins up an httptest server and returns a *backend proxying
+// into it, marked healthy with no health-check loop running, so tests can
+// control healthy/unhealthy state explicitly instead of racing a ticker.
+func newTestBackend(t *testing.T, name string, handler http.HandlerFunc) *backend {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+	b := &backend{name: name, url: target, proxy: httputil.NewSingleHostReverseProxy(target)}
+	b.healthy.Store(true)
+	return b
+}
+
+func TestMatchRouteLongestPrefixWins(t *testing.T) {
+	rt := &Router{routes: []*route{
+		{prefix: "/cam"},
+		{prefix: "/cam/sub"},
+		{prefix: "/"},
+	}}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/cam/sub/stream", "/cam/sub"},
+		{"/cam/main", "/cam"},
+		{"/other", "/"},
+	}
+	for _, tt := range tests {
+		got := rt.matchRoute(tt.path)
+		if got == nil || got.prefix != tt.want {
+			gotPrefix := "<nil>"
+			if got != nil {
+				gotPrefix = got.prefix
+			}
+			t.Errorf("matchRoute(%q) = %q, want %q", tt.path, gotPrefix, tt.want)
+		}
+	}
+}
+
+func TestMatchRouteNoMatch(t *testing.T) {
+	rt := &Router{routes: []*route{{prefix: "/cam"}}}
+	if got := rt.matchRoute("/other"); got != nil {
+		t.Errorf("matchRoute(%q) = %v, want nil", "/other", got)
+	}
+}
+
+func TestBufferBodySmallBodyIsReplayable(t *testing.T) {
+	rt := &Router{maxBufferedBody: 1024}
+	want := "hello world"
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(want))
+
+	body, replayable, err := rt.bufferBody(req)
+	if err != nil {
+		t.Fatalf("bufferBody: %v", err)
+	}
+	if !replayable {
+		t.Fatalf("replayable = false, want true")
+	}
+	if string(body) != want {
+		t.Fatalf("body = %q, want %q", body, want)
+	}
+}
+
+func TestBufferBodyOversizedFallsBackToSingleAttempt(t *testing.T) {
+	rt := &Router{maxBufferedBody: 4}
+	want := "hello world"
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(want))
+
+	body, replayable, err := rt.bufferBody(req)
+	if err != nil {
+		t.Fatalf("bufferBody: %v", err)
+	}
+	if replayable {
+		t.Fatalf("replayable = true, want false")
+	}
+	if body != nil {
+		t.Fatalf("body = %q, want nil", body)
+	}
+
+	// The original body must still be readable in full by the single
+	// attempt that gets made, even though bufferBody already drained part
+	// of it while probing the size cap.
+	got, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatalf("reading reattached body: %v", err)
+	}
+	if string(got) != want {
+		t.Fatalf("reattached body = %q, want %q", got, want)
+	}
+}
+
+func TestServeHTTPRetriesNextBackendOn5xx(t *testing.T) {
+	failing := newTestBackend(t, "failing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	healthy := newTestBackend(t, "healthy", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+
+	rt := &Router{
+		maxBufferedBody: defaultMaxBufferedBody,
+		routes: []*route{{
+			prefix:   "/",
+			backends: []*backend{failing, healthy},
+		}},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if rec.Body.String() != "ok" {
+		t.Fatalf("body = %q, want %q", rec.Body.String(), "ok")
+	}
+	if failing.metrics.retries.Load() != 1 {
+		t.Fatalf("failing backend retries = %d, want 1", failing.metrics.retries.Load())
+	}
+	if healthy.metrics.requests.Load() != 1 {
+		t.Fatalf("healthy backend requests = %d, want 1", healthy.metrics.requests.Load())
+	}
+}
+
+func TestServeHTTPNoHealthyBackend(t *testing.T) {
+	rt := &Router{routes: []*route{{prefix: "/", backends: nil}}}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestServeHTTPFlushesThroughOnSuccess(t *testing.T) {
+	healthy := newTestBackend(t, "healthy", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("chunk1"))
+		w.(http.Flusher).Flush()
+		w.Write([]byte("chunk2"))
+	})
+
+	rt := &Router{
+		maxBufferedBody: defaultMaxBufferedBody,
+		routes: []*route{{
+			prefix:   "/",
+			backends: []*backend{healthy},
+		}},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, req)
+
+	if !rec.Flushed {
+		t.Fatalf("Flushed = false, want true (streaming responses must reach the client via Flush)")
+	}
+	if rec.Body.String() != "chunk1chunk2" {
+		t.Fatalf("body = %q, want %q", rec.Body.String(), "chunk1chunk2")
+	}
+}
+
+func TestServeHTTPDiscardedAttemptHeadersDoNotLeak(t *testing.T) {
+	failing := newTestBackend(t, "failing", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-From-Backend", "failing")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("failing body"))
+	})
+	healthy := newTestBackend(t, "healthy", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-From-Backend", "healthy")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+
+	rt := &Router{
+		maxBufferedBody: defaultMaxBufferedBody,
+		routes: []*route{{
+			prefix:   "/",
+			backends: []*backend{failing, healthy},
+		}},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Header().Get("X-From-Backend"); got != "healthy" {
+		t.Fatalf("X-From-Backend = %q, want %q (discarded attempt's header leaked)", got, "healthy")
+	}
+	if rec.Body.String() != "ok" {
+		t.Fatalf("body = %q, want %q (discarded attempt's body leaked)", rec.Body.String(), "ok")
+	}
+}
+
+func TestServeHTTPNoRouteMatch(t *testing.T) {
+	rt := &Router{routes: []*route{{prefix: "/cam"}}}
+
+	req := httptest.NewRequest(http.MethodGet, "/other", nil)
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}